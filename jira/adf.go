@@ -0,0 +1,114 @@
+package jira
+
+// ADFNode represents a single node in an Atlassian Document Format tree, e.g.
+// a paragraph, a code block, or a text run carrying marks such as links.
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Content []ADFNode              `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []ADFMark              `json:"marks,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// ADFMark represents formatting or an annotation applied to a text node,
+// such as a link or emphasis.
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// ADFDoc is a builder for Atlassian Document Format comment bodies, used when
+// a Client is configured with CommentFormatADF.
+type ADFDoc struct {
+	content []ADFNode
+}
+
+// NewADFDoc starts a new, empty ADF document builder.
+func NewADFDoc() *ADFDoc {
+	return &ADFDoc{}
+}
+
+// Paragraph appends a paragraph node containing the given plain text.
+func (d *ADFDoc) Paragraph(text string) *ADFDoc {
+	d.content = append(d.content, ADFNode{
+		Type:    "paragraph",
+		Content: []ADFNode{{Type: "text", Text: text}},
+	})
+	return d
+}
+
+// CodeBlock appends a code block node containing the given source text.
+// language is optional and, when set, is attached as the block's syntax hint.
+func (d *ADFDoc) CodeBlock(code string, language string) *ADFDoc {
+	var attrs map[string]interface{}
+	if language != "" {
+		attrs = map[string]interface{}{"language": language}
+	}
+	d.content = append(d.content, ADFNode{
+		Type:    "codeBlock",
+		Attrs:   attrs,
+		Content: []ADFNode{{Type: "text", Text: code}},
+	})
+	return d
+}
+
+// Link appends a paragraph node containing a single hyperlinked text run.
+func (d *ADFDoc) Link(text string, href string) *ADFDoc {
+	d.content = append(d.content, ADFNode{
+		Type: "paragraph",
+		Content: []ADFNode{{
+			Type: "text",
+			Text: text,
+			Marks: []ADFMark{{
+				Type:  "link",
+				Attrs: map[string]interface{}{"href": href},
+			}},
+		}},
+	})
+	return d
+}
+
+// Build renders the accumulated nodes into the top-level ADF document tree
+// Jira's v3 API expects as a comment body.
+func (d *ADFDoc) Build() ADFNode {
+	return ADFNode{
+		Type:    "doc",
+		Version: 1,
+		Content: d.content,
+	}
+}
+
+// flattenText concatenates every text node under n, depth-first. UpsertBuildComment uses this
+// to find an HTML-style marker sentinel embedded as a literal text run in an ADF document, since
+// ADF has no comment node type to carry one out-of-band.
+func (n ADFNode) flattenText() string {
+	if n.Type == "text" {
+		return n.Text
+	}
+
+	var text string
+	for _, child := range n.Content {
+		text += child.flattenText()
+	}
+	return text
+}
+
+// adfMarkerNode returns a paragraph node whose sole content is the literal marker sentinel text.
+func adfMarkerNode(marker string) ADFNode {
+	return ADFNode{
+		Type:    "paragraph",
+		Content: []ADFNode{{Type: "text", Text: marker}},
+	}
+}
+
+// wrapWithMarker returns a copy of doc with start/end sentinel paragraphs for marker spliced
+// around its content, so UpsertBuildComment can find and replace the block on a later build.
+func wrapWithMarker(doc ADFNode, marker string) ADFNode {
+	content := make([]ADFNode, 0, len(doc.Content)+2)
+	content = append(content, adfMarkerNode(startMarker(marker)))
+	content = append(content, doc.Content...)
+	content = append(content, adfMarkerNode(endMarker(marker)))
+
+	return ADFNode{Type: doc.Type, Version: doc.Version, Content: content}
+}
@@ -0,0 +1,51 @@
+package jira
+
+import "testing"
+
+func TestADFDocBuild(t *testing.T) {
+	doc := NewADFDoc().
+		Paragraph("hello").
+		CodeBlock("fmt.Println()", "go").
+		Link("artifacts", "https://example.com/artifacts").
+		Build()
+
+	if doc.Type != "doc" || doc.Version != 1 {
+		t.Fatalf("Build() = %+v, want type %q version 1", doc, "doc")
+	}
+
+	if len(doc.Content) != 3 {
+		t.Fatalf("Build() content length = %d, want 3", len(doc.Content))
+	}
+
+	paragraph := doc.Content[0]
+	if paragraph.Type != "paragraph" || paragraph.Content[0].Text != "hello" {
+		t.Errorf("paragraph node = %+v, want text %q", paragraph, "hello")
+	}
+
+	codeBlock := doc.Content[1]
+	if codeBlock.Type != "codeBlock" || codeBlock.Attrs["language"] != "go" || codeBlock.Content[0].Text != "fmt.Println()" {
+		t.Errorf("codeBlock node = %+v, want language %q and text %q", codeBlock, "go", "fmt.Println()")
+	}
+
+	link := doc.Content[2]
+	linkText := link.Content[0]
+	if len(linkText.Marks) != 1 || linkText.Marks[0].Type != "link" || linkText.Marks[0].Attrs["href"] != "https://example.com/artifacts" {
+		t.Errorf("link text marks = %+v, want a link mark to %q", linkText.Marks, "https://example.com/artifacts")
+	}
+}
+
+func TestADFDocCodeBlockWithoutLanguage(t *testing.T) {
+	doc := NewADFDoc().CodeBlock("no lang", "").Build()
+
+	if doc.Content[0].Attrs != nil {
+		t.Errorf("Attrs = %+v, want nil when language is empty", doc.Content[0].Attrs)
+	}
+}
+
+func TestADFNodeFlattenText(t *testing.T) {
+	doc := NewADFDoc().Paragraph("one").Paragraph("two").Build()
+
+	if got, want := doc.flattenText(), "onetwo"; got != want {
+		t.Errorf("flattenText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestBasicAuthenticatorAuthenticate(t *testing.T) {
+	auth := NewBasicAuthenticator("user@example.com", "api-token")
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() error = %v, want nil", err)
+	}
+
+	user, pass, ok := req.BasicAuth()
+	if !ok {
+		t.Fatalf("req has no Basic auth header set")
+	}
+	if user != "user@example.com" || pass != "api-token" {
+		t.Errorf("BasicAuth() = (%q, %q), want (%q, %q)", user, pass, "user@example.com", "api-token")
+	}
+}
+
+func TestBearerAuthenticatorAuthenticate(t *testing.T) {
+	auth := NewBearerAuthenticator("pat-123")
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() error = %v, want nil", err)
+	}
+
+	if got, want := req.Header.Get("Authorization"), "Bearer pat-123"; got != want {
+		t.Errorf("Authorization header = %q, want %q", got, want)
+	}
+}
+
+type fakeTokenSource struct {
+	token *oauth2.Token
+	err   error
+}
+
+func (f fakeTokenSource) Token() (*oauth2.Token, error) {
+	return f.token, f.err
+}
+
+func TestOAuth2AuthenticatorAuthenticate(t *testing.T) {
+	auth := NewOAuth2Authenticator(fakeTokenSource{token: &oauth2.Token{AccessToken: "access-token", TokenType: "Bearer"}})
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("Authenticate() error = %v, want nil", err)
+	}
+
+	if got, want := req.Header.Get("Authorization"), "Bearer access-token"; got != want {
+		t.Errorf("Authorization header = %q, want %q", got, want)
+	}
+}
+
+func TestOAuth2AuthenticatorAuthenticateTokenError(t *testing.T) {
+	wantErr := errors.New("token refresh failed")
+	auth := NewOAuth2Authenticator(fakeTokenSource{err: wantErr})
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+
+	err := auth.Authenticate(req)
+	if err == nil {
+		t.Fatal("Authenticate() error = nil, want an error surfacing the TokenSource failure")
+	}
+	if !strings.Contains(err.Error(), wantErr.Error()) {
+		t.Errorf("Authenticate() error = %v, want it to mention %v", err, wantErr)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("Authorization header = %q, want unset when Token() fails", req.Header.Get("Authorization"))
+	}
+}
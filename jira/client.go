@@ -2,12 +2,16 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/bitrise-io/go-utils/colorstring"
 	"github.com/bitrise-io/go-utils/log"
@@ -16,73 +20,211 @@ import (
 
 const (
 	apiEndPoint     = "/rest/api/2/issue/"
+	apiEndPointV3   = "/rest/api/3/issue/"
 	commentEndPoint = "/comment"
+
+	// defaultMaxParallel is the number of comments PostIssueComments posts concurrently
+	// when WithMaxParallel isn't used to override it.
+	defaultMaxParallel = 4
+	// maxRetries is how many times performRequest retries a failed or transient-error request
+	// before giving up.
+	maxRetries = 5
+	// baseRetryBackoff is the delay before the first retry; later retries back off exponentially.
+	baseRetryBackoff = 500 * time.Millisecond
+)
+
+// CommentFormat selects the Jira API version and comment body shape a Client uses.
+type CommentFormat int
+
+const (
+	// CommentFormatPlain posts Comment.Content as a plain-text v2 API comment body. This is the default.
+	CommentFormatPlain CommentFormat = iota
+	// CommentFormatWiki posts Comment.Content as Jira wiki markup via the v2 API.
+	CommentFormatWiki
+	// CommentFormatADF posts comments as Atlassian Document Format via the v3 API, required by Jira Cloud
+	// for structured content such as tables and code blocks.
+	CommentFormatADF
 )
 
 // Client ...
 type Client struct {
-	token   string
-	client  *http.Client
-	headers map[string]string
-	baseURL string
+	auth              Authenticator
+	client            *http.Client
+	headers           map[string]string
+	baseURL           string
+	commentFormat     CommentFormat
+	maxParallel       int
+	validateIssues    bool
+	skipClosedIssues  bool
+	defaultVisibility *Visibility
+}
+
+// ClientOption configures optional Client behaviour, applied in NewClient.
+type ClientOption func(*Client)
+
+// WithCommentFormat sets the comment body format (and thus the Jira API version) a Client uses.
+func WithCommentFormat(format CommentFormat) ClientOption {
+	return func(client *Client) {
+		client.commentFormat = format
+	}
+}
+
+// WithMaxParallel sets the number of comments PostIssueComments posts concurrently. The default
+// is 4, to stay well under Jira Cloud's per-user and per-tenant rate limits.
+func WithMaxParallel(maxParallel int) ClientOption {
+	return func(client *Client) {
+		client.maxParallel = maxParallel
+	}
+}
+
+// WithIssueValidation makes PostIssueComments fetch each comment's issue before posting to it,
+// failing fast with the full list of non-existent issue keys instead of getting a 404 per
+// comment, and filling in Comment.Issue for the comments it does post. When skipClosed is true,
+// issues whose status is Closed are skipped instead of receiving a comment.
+func WithIssueValidation(skipClosed bool) ClientOption {
+	return func(client *Client) {
+		client.validateIssues = true
+		client.skipClosedIssues = skipClosed
+	}
+}
+
+// WithDefaultVisibility restricts every comment posted by this Client to visibility, unless a
+// Comment sets its own Visibility. Configure this once per project instead of on every Comment.
+func WithDefaultVisibility(visibility Visibility) ClientOption {
+	return func(client *Client) {
+		client.defaultVisibility = &visibility
+	}
 }
 
 // Comment ...
 type Comment struct {
 	Content string
 	IssuKey string
+	// ADF holds a pre-built Atlassian Document Format body (see NewADFDoc) and is only used
+	// when the owning Client was created with WithCommentFormat(CommentFormatADF). When nil,
+	// Content is wrapped in a single-paragraph ADF document instead.
+	ADF *ADFNode
+	// Marker, when set, identifies this as a build-detail comment that UpsertBuildComment
+	// should update in place rather than duplicate across builds.
+	Marker string
+	// Issue is filled in on PostIssueComments' own copies of the comments when the Client was
+	// created with WithIssueValidation, and adds the issue's assignee and status to the logged
+	// result. The caller's comments are not modified.
+	Issue *Issue
+	// Visibility restricts who can see this comment, overriding the Client's default visibility
+	// (see WithDefaultVisibility).
+	// Leave nil to use the Client's default, or to leave the comment visible to everyone if none
+	// is set.
+	Visibility *Visibility
+}
+
+// Visibility restricts a comment to members of a given Jira role or group, e.g. so build-detail
+// comments containing stack traces or internal URLs can be hidden from customers in a Jira
+// Service Management portal.
+type Visibility struct {
+	// Type is "role" or "group".
+	Type string
+	// Value is the role or group name, e.g. "Developers".
+	Value string
 }
 
 type response struct {
 	issueKey string
 	err      error
+	issue    *Issue
 }
 
 func (resp response) String() string {
 	respValue := map[bool]string{true: colorstring.Green("SUCCES"), false: colorstring.Red("FAILED")}[resp.err == nil]
-	return fmt.Sprintf("Posting comment to - %s - : %s", resp.issueKey, respValue)
+	msg := fmt.Sprintf("Posting comment to - %s - : %s", resp.issueKey, respValue)
+	if resp.issue != nil {
+		assignee := "unassigned"
+		if resp.issue.Fields.Assignee != nil {
+			assignee = resp.issue.Fields.Assignee.DisplayName
+		}
+		msg += fmt.Sprintf(" (assignee: %s, status: %s)", assignee, resp.issue.Fields.Status.Name)
+	}
+	return msg
 }
 
 // -------------------------------------
 // -- Public methods
 
 // NewClient ...
-func NewClient(token, baseURL string) *Client {
-	return &Client{
-		token:  token,
+func NewClient(auth Authenticator, baseURL string, opts ...ClientOption) *Client {
+	client := &Client{
+		auth:   auth,
 		client: &http.Client{},
 		headers: map[string]string{
-			"Authorization": `Basic ` + token,
-			"Content-Type":  "application/json",
+			"Content-Type": "application/json",
 		},
 		baseURL: baseURL,
 	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
 
-// PostIssueComments ...
-func (client *Client) PostIssueComments(comments []Comment) error {
+// PostIssueComments posts each comment to its issue using up to the number of concurrent workers
+// set by WithMaxParallel (default 4), retrying transient failures with backoff. It returns once every comment has been
+// attempted, or as soon as ctx is cancelled.
+func (client *Client) PostIssueComments(ctx context.Context, comments []Comment) error {
 	if len(comments) == 0 {
 		return fmt.Errorf("no comment has been added")
 	}
 
-	ch := make(chan response, len(comments))
+	maxParallel := client.maxParallel
+	if maxParallel <= 0 {
+		maxParallel = defaultMaxParallel
+	}
+	if maxParallel > len(comments) {
+		maxParallel = len(comments)
+	}
+
+	if client.validateIssues {
+		var err error
+		comments, err = client.validateComments(ctx, comments, maxParallel)
+		if err != nil {
+			return err
+		}
+		if len(comments) == 0 {
+			return fmt.Errorf("no comment has been added")
+		}
+	}
+
+	jobs := make(chan Comment, len(comments))
 	for _, comment := range comments {
-		go client.postIssueComment(comment, ch)
+		jobs <- comment
+	}
+	close(jobs)
+
+	ch := make(chan response, len(comments))
+	var workers sync.WaitGroup
+	for i := 0; i < maxParallel; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for comment := range jobs {
+				client.postIssueComment(ctx, comment, ch)
+			}
+		}()
 	}
 
-	counter := 0
+	go func() {
+		workers.Wait()
+		close(ch)
+	}()
+
 	var respErrors []response
 	for resp := range ch {
-		counter++
 		log.Printf(resp.String())
 
 		if resp.err != nil {
 			respErrors = append(respErrors, resp)
 		}
-
-		if counter >= len(comments) {
-			break
-		}
 	}
 
 	if len(respErrors) > 0 {
@@ -102,33 +244,68 @@ func (client *Client) PostIssueComments(comments []Comment) error {
 // -------------------------------------
 // -- Private methods
 
-func (client *Client) postIssueComment(comment Comment, ch chan response) {
+func (client *Client) postIssueComment(ctx context.Context, comment Comment, ch chan response) {
 	t := strings.TrimSpace(comment.IssuKey)
-	requestURL, err := urlutil.Join(client.baseURL, apiEndPoint, t, commentEndPoint)
+
+	endpoint := client.apiEndpoint()
+	content := comment.Content
+	if comment.Marker != "" && client.commentFormat != CommentFormatADF {
+		// Embed the marker sentinels so a later UpsertBuildComment call can find this comment.
+		content = spliceMarkedContent("", comment.Marker, content)
+	}
+	fields := map[string]interface{}{"body": content}
+	if client.commentFormat == CommentFormatADF {
+		body := comment.ADF
+		if body == nil {
+			doc := NewADFDoc().Paragraph(comment.Content).Build()
+			body = &doc
+		}
+		if comment.Marker != "" {
+			wrapped := wrapWithMarker(*body, comment.Marker)
+			body = &wrapped
+		}
+		fields = map[string]interface{}{"body": body}
+	}
+
+	client.applyVisibility(fields, comment)
+
+	requestURL, err := urlutil.Join(client.baseURL, endpoint, t, commentEndPoint)
 	if err != nil {
-		ch <- response{comment.IssuKey, err}
+		ch <- response{comment.IssuKey, err, comment.Issue}
 		return
 	}
 
-	fields := map[string]interface{}{"body": comment.Content}
-
-	request, err := createRequest(http.MethodPost, requestURL, client.headers, fields)
+	request, err := client.newRequest(http.MethodPost, requestURL, fields)
 	if err != nil {
-		ch <- response{comment.IssuKey, err}
+		ch <- response{comment.IssuKey, err, comment.Issue}
 		return
 	}
 
 	requestBytes, err := httputil.DumpRequest(request, true)
 	if err != nil {
-		ch <- response{comment.IssuKey, err}
+		ch <- response{comment.IssuKey, err, comment.Issue}
 		return
 	}
 	log.Debugf("Request: %v", string(requestBytes))
 
 	// Perform request
-	_, body, err := client.performRequest(request, nil)
+	_, body, err := client.performRequest(ctx, comment.IssuKey, request, nil)
 	log.Debugf("Body: %s", string(body))
-	ch <- response{comment.IssuKey, err}
+	ch <- response{comment.IssuKey, err, comment.Issue}
+}
+
+// newRequest builds a request against the Jira API and attaches the client's credentials to it.
+func (client *Client) newRequest(requestMethod string, url string, fields map[string]interface{}) (*http.Request, error) {
+	req, err := createRequest(requestMethod, url, client.headers, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.auth.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request, error: %s", err)
+	}
+
+	return req, nil
 }
 
 func createRequest(requestMethod string, url string, headers map[string]string, fields map[string]interface{}) (*http.Request, error) {
@@ -150,13 +327,68 @@ func createRequest(requestMethod string, url string, headers map[string]string,
 	return req, nil
 }
 
-func (client *Client) performRequest(req *http.Request, requestResponse interface{}) (interface{}, []byte, error) {
-	response, err := client.client.Do(req)
+// performRequest executes req, retrying transient failures (network errors, 429s honoring
+// Jira's Retry-After header, and 5xx responses) with exponential backoff until maxRetries is
+// reached or ctx is cancelled.
+func (client *Client) performRequest(ctx context.Context, issueKey string, req *http.Request, requestResponse interface{}) (interface{}, []byte, error) {
+	req = req.WithContext(ctx)
+
+	var httpResp *http.Response
+	var body []byte
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		httpResp, body, err = client.doRequest(req)
+
+		retryable := err != nil || httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempt >= maxRetries {
+			break
+		}
+
+		wait := retryDelay(httpResp, attempt)
+		log.Warnf("Request to %s failed (attempt %d/%d), retrying in %s", req.URL, attempt+1, maxRetries+1, wait)
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			newBody, berr := req.GetBody()
+			if berr != nil {
+				return nil, nil, fmt.Errorf("failed to rewind request body for retry, error: %s", berr)
+			}
+			req.Body = newBody
+		}
+	}
+
 	if err != nil {
 		// On error, any Response can be ignored
 		return nil, nil, fmt.Errorf("failed to perform request, error: %s", err)
 	}
 
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode > http.StatusMultipleChoices {
+		return nil, nil, newError(httpResp.StatusCode, issueKey, body)
+	}
+
+	// Parse JSON body
+	if requestResponse != nil {
+		if err := json.Unmarshal(body, &requestResponse); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal response (%s), error: %s", body, err)
+		}
+
+		logDebugPretty(&requestResponse)
+	}
+	return requestResponse, body, nil
+}
+
+func (client *Client) doRequest(req *http.Request) (*http.Response, []byte, error) {
+	response, err := client.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// The client must close the response body when finished with it
 	defer func() {
 		if cerr := response.Body.Close(); cerr != nil {
@@ -166,22 +398,46 @@ func (client *Client) performRequest(req *http.Request, requestResponse interfac
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read response body, error: %s", err)
+		return response, nil, err
 	}
 
-	if response.StatusCode < http.StatusOK || response.StatusCode > http.StatusMultipleChoices {
-		return nil, nil, fmt.Errorf("Response status: %d - Body: %s", response.StatusCode, string(body))
-	}
+	return response, body, nil
+}
 
-	// Parse JSON body
-	if requestResponse != nil {
-		if err := json.Unmarshal([]byte(body), &requestResponse); err != nil {
-			return nil, nil, fmt.Errorf("failed to unmarshal response (%s), error: %s", body, err)
+// retryDelay returns how long to wait before retrying after response, honoring Jira's
+// Retry-After header on 429s and otherwise backing off exponentially from baseRetryBackoff.
+func retryDelay(response *http.Response, attempt int) time.Duration {
+	if response != nil && response.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := response.Header.Get("Retry-After"); retryAfter != "" {
+			if secs, err := strconv.Atoi(retryAfter); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+			if at, err := http.ParseTime(retryAfter); err == nil {
+				if d := time.Until(at); d > 0 {
+					return d
+				}
+			}
 		}
+	}
 
-		logDebugPretty(&requestResponse)
+	return baseRetryBackoff * time.Duration(1<<uint(attempt))
+}
+
+// resolveVisibility returns comment's own Visibility, falling back to the Client's default
+// visibility (see WithDefaultVisibility), or nil if neither is set.
+func (client *Client) resolveVisibility(comment Comment) *Visibility {
+	if comment.Visibility != nil {
+		return comment.Visibility
+	}
+	return client.defaultVisibility
+}
+
+// applyVisibility sets fields["visibility"] from comment's resolved Visibility, leaving fields
+// unchanged if neither comment nor the Client has one configured.
+func (client *Client) applyVisibility(fields map[string]interface{}, comment Comment) {
+	if visibility := client.resolveVisibility(comment); visibility != nil {
+		fields["visibility"] = map[string]string{"type": visibility.Type, "value": visibility.Value}
 	}
-	return requestResponse, body, nil
 }
 
 func addHeaders(req *http.Request, headers map[string]string) {
@@ -0,0 +1,138 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	t.Run("exponential backoff without a response", func(t *testing.T) {
+		for attempt, want := range map[int]time.Duration{
+			0: baseRetryBackoff,
+			1: 2 * baseRetryBackoff,
+			2: 4 * baseRetryBackoff,
+		} {
+			if got := retryDelay(nil, attempt); got != want {
+				t.Errorf("retryDelay(nil, %d) = %s, want %s", attempt, got, want)
+			}
+		}
+	})
+
+	t.Run("exponential backoff for a non-429 response", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}}
+
+		if got, want := retryDelay(resp, 1), 2*baseRetryBackoff; got != want {
+			t.Errorf("retryDelay(500, 1) = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("honors a numeric Retry-After header on 429", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"30"}},
+		}
+
+		if got, want := retryDelay(resp, 0), 30*time.Second; got != want {
+			t.Errorf("retryDelay(429, 0) = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("falls back to exponential backoff when Retry-After is missing or unparsable", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+
+		if got, want := retryDelay(resp, 3), 8*baseRetryBackoff; got != want {
+			t.Errorf("retryDelay(429 no header, 3) = %s, want %s", got, want)
+		}
+
+		resp.Header.Set("Retry-After", "not-a-number-or-http-date")
+		if got, want := retryDelay(resp, 3), 8*baseRetryBackoff; got != want {
+			t.Errorf("retryDelay(429 bad header, 3) = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestPostIssueCommentsBoundsConcurrency(t *testing.T) {
+	const maxParallel = 2
+
+	var current, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL, WithMaxParallel(maxParallel))
+
+	var comments []Comment
+	for i := 0; i < 6; i++ {
+		comments = append(comments, Comment{IssuKey: fmt.Sprintf("PROJ-%d", i)})
+	}
+
+	if err := client.PostIssueComments(context.Background(), comments); err != nil {
+		t.Fatalf("PostIssueComments() error = %v, want nil", err)
+	}
+
+	if peak > maxParallel {
+		t.Errorf("peak concurrent requests = %d, want <= %d (MaxParallel)", peak, maxParallel)
+	}
+	if peak < maxParallel {
+		t.Errorf("peak concurrent requests = %d, want workers to actually overlap to %d", peak, maxParallel)
+	}
+}
+
+func TestPostIssueCommentsRetriesOn429(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+
+	if err := client.PostIssueComments(context.Background(), []Comment{{IssuKey: "PROJ-1"}}); err != nil {
+		t.Fatalf("PostIssueComments() error = %v, want nil after retrying past the 429", err)
+	}
+	if got, want := atomic.LoadInt32(&attempts), int32(2); got != want {
+		t.Errorf("server saw %d attempts, want %d (initial 429 + retried 200)", got, want)
+	}
+}
+
+func TestPostIssueCommentsCtxCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+
+	start := time.Now()
+	err := client.PostIssueComments(ctx, []Comment{{IssuKey: "PROJ-1"}})
+	if err == nil {
+		t.Fatal("PostIssueComments() error = nil, want an error once ctx is cancelled mid-batch")
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("PostIssueComments() took %s, want it to abort promptly after ctx cancellation", elapsed)
+	}
+}
@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Error is returned by performRequest when Jira responds with an HTTP error status. It carries
+// the status code, the issue key the request was for (if any), and Jira's own parsed error
+// messages, so callers can distinguish e.g. a missing issue (404) from a permissions problem
+// (403) without string-matching the response body.
+type Error struct {
+	StatusCode    int
+	IssueKey      string
+	ErrorMessages []string
+	Errors        map[string]string
+	Body          string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Response status: %d - Body: %s", e.StatusCode, e.Body)
+}
+
+// jiraErrorBody mirrors Jira's documented JSON error body shape.
+type jiraErrorBody struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// newError builds an Error from a failed response, parsing Jira's error body when it matches
+// the documented shape and leaving ErrorMessages/Errors empty otherwise.
+func newError(statusCode int, issueKey string, body []byte) *Error {
+	jiraErr := &Error{
+		StatusCode: statusCode,
+		IssueKey:   issueKey,
+		Body:       string(body),
+	}
+
+	var parsed jiraErrorBody
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		jiraErr.ErrorMessages = parsed.ErrorMessages
+		jiraErr.Errors = parsed.Errors
+	}
+
+	return jiraErr
+}
+
+// IsNotFound reports whether err is a jira.Error for a 404 response, e.g. the issue doesn't exist.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, 404)
+}
+
+// IsUnauthorized reports whether err is a jira.Error for a 401 (not authenticated) or
+// 403 (permission denied) response.
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, 401) || hasStatusCode(err, 403)
+}
+
+// IsRateLimited reports whether err is a jira.Error for a 429 response.
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, 429)
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var jiraErr *Error
+	if errors.As(err, &jiraErr) {
+		return jiraErr.StatusCode == statusCode
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package jira
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorClassifiers(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		isNotFound     bool
+		isUnauthorized bool
+		isRateLimited  bool
+	}{
+		{name: "404", err: newError(404, "PROJ-1", []byte(`{}`)), isNotFound: true},
+		{name: "401", err: newError(401, "PROJ-1", []byte(`{}`)), isUnauthorized: true},
+		{name: "403", err: newError(403, "PROJ-1", []byte(`{}`)), isUnauthorized: true},
+		{name: "429", err: newError(429, "PROJ-1", []byte(`{}`)), isRateLimited: true},
+		{name: "500", err: newError(500, "PROJ-1", []byte(`{}`))},
+		{name: "non-jira error", err: errors.New("boom")},
+		{name: "nil", err: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsNotFound(test.err); got != test.isNotFound {
+				t.Errorf("IsNotFound(%v) = %v, want %v", test.err, got, test.isNotFound)
+			}
+			if got := IsUnauthorized(test.err); got != test.isUnauthorized {
+				t.Errorf("IsUnauthorized(%v) = %v, want %v", test.err, got, test.isUnauthorized)
+			}
+			if got := IsRateLimited(test.err); got != test.isRateLimited {
+				t.Errorf("IsRateLimited(%v) = %v, want %v", test.err, got, test.isRateLimited)
+			}
+		})
+	}
+}
+
+func TestNewErrorParsesJiraErrorBody(t *testing.T) {
+	body := []byte(`{"errorMessages":["Issue does not exist"],"errors":{"summary":"is required"}}`)
+	jiraErr := newError(400, "PROJ-1", body)
+
+	if len(jiraErr.ErrorMessages) != 1 || jiraErr.ErrorMessages[0] != "Issue does not exist" {
+		t.Errorf("ErrorMessages = %v, want [%q]", jiraErr.ErrorMessages, "Issue does not exist")
+	}
+	if jiraErr.Errors["summary"] != "is required" {
+		t.Errorf("Errors[summary] = %q, want %q", jiraErr.Errors["summary"], "is required")
+	}
+	if jiraErr.IssueKey != "PROJ-1" {
+		t.Errorf("IssueKey = %q, want %q", jiraErr.IssueKey, "PROJ-1")
+	}
+}
+
+func TestNewErrorNonJSONBody(t *testing.T) {
+	jiraErr := newError(500, "PROJ-1", []byte("internal server error"))
+
+	if jiraErr.ErrorMessages != nil || jiraErr.Errors != nil {
+		t.Errorf("got ErrorMessages=%v Errors=%v, want both nil for a non-JSON body", jiraErr.ErrorMessages, jiraErr.Errors)
+	}
+	if jiraErr.Body != "internal server error" {
+		t.Errorf("Body = %q, want %q", jiraErr.Body, "internal server error")
+	}
+}
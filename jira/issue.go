@@ -0,0 +1,132 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/urlutil"
+)
+
+// issueFields lists the fields GetIssue asks Jira to return.
+const issueFields = "summary,status,assignee,fixVersions"
+
+// Issue is the subset of a Jira issue's fields GetIssue fetches.
+type Issue struct {
+	Key    string      `json:"key"`
+	Fields IssueFields `json:"fields"`
+}
+
+// IssueFields ...
+type IssueFields struct {
+	Summary     string       `json:"summary"`
+	Status      IssueStatus  `json:"status"`
+	Assignee    *IssueUser   `json:"assignee"`
+	FixVersions []FixVersion `json:"fixVersions"`
+}
+
+// IssueStatus ...
+type IssueStatus struct {
+	Name string `json:"name"`
+}
+
+// IssueUser ...
+type IssueUser struct {
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// FixVersion ...
+type FixVersion struct {
+	Name string `json:"name"`
+}
+
+// GetIssue fetches key's Summary, Status, Assignee and FixVersions from Jira.
+func (client *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	t := strings.TrimSpace(key)
+
+	requestURL, err := urlutil.Join(client.baseURL, client.apiEndpoint(), t)
+	if err != nil {
+		return nil, err
+	}
+	requestURL += "?fields=" + issueFields
+
+	request, err := client.newRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	if _, _, err := client.performRequest(ctx, t, request, &issue); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+// validatedComment pairs a Comment with the outcome of fetching its issue.
+type validatedComment struct {
+	comment Comment
+	issue   *Issue
+	err     error
+}
+
+// validateComments fetches every comment's issue (bounded by maxParallel) and returns the
+// comments that should still be posted, with Comment.Issue filled in. If any issue key doesn't
+// exist in Jira, it returns an error listing all of them rather than posting to the rest and
+// letting the missing ones fail individually later.
+func (client *Client) validateComments(ctx context.Context, comments []Comment, maxParallel int) ([]Comment, error) {
+	jobs := make(chan Comment, len(comments))
+	for _, comment := range comments {
+		jobs <- comment
+	}
+	close(jobs)
+
+	results := make(chan validatedComment, len(comments))
+	var workers sync.WaitGroup
+	for i := 0; i < maxParallel; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for comment := range jobs {
+				issue, err := client.GetIssue(ctx, comment.IssuKey)
+				results <- validatedComment{comment: comment, issue: issue, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	var missingKeys []string
+	var validated []Comment
+	for result := range results {
+		if result.err != nil {
+			if IsNotFound(result.err) {
+				missingKeys = append(missingKeys, result.comment.IssuKey)
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch issue %s, error: %s", result.comment.IssuKey, result.err)
+		}
+
+		if client.skipClosedIssues && strings.EqualFold(result.issue.Fields.Status.Name, "Closed") {
+			log.Warnf("Skipping closed issue %s", result.comment.IssuKey)
+			continue
+		}
+
+		comment := result.comment
+		comment.Issue = result.issue
+		validated = append(validated, comment)
+	}
+
+	if len(missingKeys) > 0 {
+		return nil, fmt.Errorf("issue(s) not found in Jira: %s", strings.Join(missingKeys, ", "))
+	}
+
+	return validated, nil
+}
@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type noopAuthenticator struct{}
+
+func (noopAuthenticator) Authenticate(*http.Request) error { return nil }
+
+func issueServer(t *testing.T, statusByKey map[string]string, failKey string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+
+		if key == failKey {
+			// A non-retryable 4xx, so the test isn't slowed down by performRequest's backoff loop.
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status, ok := statusByKey[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		issue := Issue{Key: key, Fields: IssueFields{Status: IssueStatus{Name: status}}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(issue)
+	}))
+}
+
+func TestValidateCommentsAllKeysExist(t *testing.T) {
+	server := issueServer(t, map[string]string{"PROJ-1": "Open", "PROJ-2": "Open"}, "")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+	comments := []Comment{{IssuKey: "PROJ-1"}, {IssuKey: "PROJ-2"}}
+
+	validated, err := client.validateComments(context.Background(), comments, 2)
+	if err != nil {
+		t.Fatalf("validateComments() error = %v, want nil", err)
+	}
+	if len(validated) != 2 {
+		t.Fatalf("validateComments() returned %d comments, want 2", len(validated))
+	}
+	for _, comment := range validated {
+		if comment.Issue == nil {
+			t.Errorf("comment %s has no Issue filled in", comment.IssuKey)
+		}
+	}
+}
+
+func TestValidateCommentsMissingKey(t *testing.T) {
+	server := issueServer(t, map[string]string{"PROJ-1": "Open"}, "")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+	comments := []Comment{{IssuKey: "PROJ-1"}, {IssuKey: "PROJ-MISSING"}}
+
+	_, err := client.validateComments(context.Background(), comments, 2)
+	if err == nil {
+		t.Fatal("validateComments() error = nil, want an error listing the missing key")
+	}
+	if !strings.Contains(err.Error(), "PROJ-MISSING") {
+		t.Errorf("validateComments() error = %q, want it to mention %q", err.Error(), "PROJ-MISSING")
+	}
+}
+
+func TestValidateCommentsNonNotFoundFailure(t *testing.T) {
+	server := issueServer(t, map[string]string{"PROJ-1": "Open"}, "PROJ-2")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+	comments := []Comment{{IssuKey: "PROJ-1"}, {IssuKey: "PROJ-2"}}
+
+	_, err := client.validateComments(context.Background(), comments, 2)
+	if err == nil {
+		t.Fatal("validateComments() error = nil, want the 400 from PROJ-2 to short-circuit validation")
+	}
+	if !strings.Contains(err.Error(), "PROJ-2") {
+		t.Errorf("validateComments() error = %q, want it to mention %q", err.Error(), "PROJ-2")
+	}
+}
+
+func TestValidateCommentsSkipsClosedIssues(t *testing.T) {
+	server := issueServer(t, map[string]string{"PROJ-1": "Open", "PROJ-2": "Closed"}, "")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL, WithIssueValidation(true))
+	comments := []Comment{{IssuKey: "PROJ-1"}, {IssuKey: "PROJ-2"}}
+
+	validated, err := client.validateComments(context.Background(), comments, 2)
+	if err != nil {
+		t.Fatalf("validateComments() error = %v, want nil", err)
+	}
+
+	var keys []string
+	for _, comment := range validated {
+		keys = append(keys, comment.IssuKey)
+	}
+	sort.Strings(keys)
+
+	if len(keys) != 1 || keys[0] != "PROJ-1" {
+		t.Errorf("validateComments() returned keys %v, want only [PROJ-1] with PROJ-2 skipped as Closed", keys)
+	}
+}
+
+func TestGetIssue(t *testing.T) {
+	server := issueServer(t, map[string]string{"PROJ-1": "Open"}, "")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+
+	issue, err := client.GetIssue(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v, want nil", err)
+	}
+	if issue.Key != "PROJ-1" || issue.Fields.Status.Name != "Open" {
+		t.Errorf("GetIssue() = %+v, want key %q status %q", issue, "PROJ-1", "Open")
+	}
+}
+
+func TestGetIssueNotFound(t *testing.T) {
+	server := issueServer(t, map[string]string{}, "")
+	defer server.Close()
+
+	client := NewClient(noopAuthenticator{}, server.URL)
+
+	_, err := client.GetIssue(context.Background(), "PROJ-MISSING")
+	if !IsNotFound(err) {
+		t.Errorf("GetIssue() error = %v, want IsNotFound(err) to be true", err)
+	}
+}
@@ -0,0 +1,221 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/urlutil"
+)
+
+// commentPageSize is the page size used to list an issue's existing comments when searching for
+// a marked one; Jira paginates this endpoint, so findMarkedComment walks every page.
+const commentPageSize = 50
+
+// existingComment is a single entry from a GET .../comment listing response, with Body left
+// raw since it may be a plain string (v2) or an ADF object (v3).
+type existingComment struct {
+	ID   string          `json:"id"`
+	Body json.RawMessage `json:"body"`
+}
+
+type commentListResponse struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	Comments   []existingComment `json:"comments"`
+}
+
+func startMarker(marker string) string { return fmt.Sprintf("<!-- %s start -->", marker) }
+func endMarker(marker string) string   { return fmt.Sprintf("<!-- %s end -->", marker) }
+
+// UpsertBuildComment posts comment as a new issue comment, unless comment.Marker is set and an
+// existing comment on the issue already contains that marker - in which case the marker-delimited
+// section of that comment is replaced in place instead, so repeated builds update a single comment
+// rather than accumulating one comment per build. This works for both plain/wiki comments (the
+// marker is matched against the literal body string) and ADF comments (the marker is embedded as
+// a text run, since ADF has no comment node type to carry one out-of-band).
+func (client *Client) UpsertBuildComment(ctx context.Context, comment Comment) error {
+	if comment.Marker == "" {
+		return client.PostIssueComments(ctx, []Comment{comment})
+	}
+
+	existing, err := client.findMarkedComment(ctx, comment.IssuKey, comment.Marker)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		ch := make(chan response, 1)
+		client.postIssueComment(ctx, comment, ch)
+		resp := <-ch
+		return resp.err
+	}
+
+	newBody, err := client.spliceMarkedBody(existing.Body, comment)
+	if err != nil {
+		return fmt.Errorf("failed to read existing comment %s body, error: %s", existing.ID, err)
+	}
+
+	return client.putIssueComment(ctx, comment, existing.ID, newBody)
+}
+
+// findMarkedComment pages through issueKey's comments (oldest first, Jira's default order) and
+// returns the first one whose body contains marker's start sentinel, or nil if none do.
+func (client *Client) findMarkedComment(ctx context.Context, issueKey, marker string) (*existingComment, error) {
+	t := strings.TrimSpace(issueKey)
+	start := startMarker(marker)
+
+	for startAt := 0; ; startAt += commentPageSize {
+		requestURL, err := urlutil.Join(client.baseURL, client.apiEndpoint(), t, commentEndPoint)
+		if err != nil {
+			return nil, err
+		}
+		requestURL += fmt.Sprintf("?startAt=%d&maxResults=%d", startAt, commentPageSize)
+
+		request, err := client.newRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list commentListResponse
+		if _, _, err := client.performRequest(ctx, t, request, &list); err != nil {
+			return nil, err
+		}
+
+		for _, c := range list.Comments {
+			if client.commentFormat == CommentFormatADF {
+				var doc ADFNode
+				if err := json.Unmarshal(c.Body, &doc); err != nil {
+					continue
+				}
+				if strings.Contains(doc.flattenText(), start) {
+					return &c, nil
+				}
+				continue
+			}
+
+			var body string
+			if err := json.Unmarshal(c.Body, &body); err != nil {
+				continue
+			}
+			if strings.Contains(body, start) {
+				return &c, nil
+			}
+		}
+
+		if len(list.Comments) == 0 || startAt+len(list.Comments) >= list.Total {
+			return nil, nil
+		}
+	}
+}
+
+// spliceMarkedBody returns the full body to PUT back to Jira with the marker-delimited section
+// replaced by comment's content: a plain string for v2/wiki comments, or an ADF document for v3
+// (ADF) comments, matching whatever format existingBody was decoded from.
+func (client *Client) spliceMarkedBody(existingBody json.RawMessage, comment Comment) (interface{}, error) {
+	if client.commentFormat != CommentFormatADF {
+		var existing string
+		if err := json.Unmarshal(existingBody, &existing); err != nil {
+			return nil, err
+		}
+		return spliceMarkedContent(existing, comment.Marker, comment.Content), nil
+	}
+
+	var existing ADFNode
+	if err := json.Unmarshal(existingBody, &existing); err != nil {
+		return nil, err
+	}
+	return spliceMarkedADF(existing, comment), nil
+}
+
+func (client *Client) putIssueComment(ctx context.Context, comment Comment, commentID string, body interface{}) error {
+	requestURL, err := urlutil.Join(client.baseURL, client.apiEndpoint(), strings.TrimSpace(comment.IssuKey), commentEndPoint, commentID)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{"body": body}
+	client.applyVisibility(fields, comment)
+
+	request, err := client.newRequest(http.MethodPut, requestURL, fields)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.performRequest(ctx, comment.IssuKey, request, nil)
+	return err
+}
+
+// apiEndpoint returns the issue API path prefix to use, the v3 API when the client is
+// configured for ADF and the v2 API otherwise.
+func (client *Client) apiEndpoint() string {
+	if client.commentFormat == CommentFormatADF {
+		return apiEndPointV3
+	}
+	return apiEndPoint
+}
+
+// spliceMarkedContent returns existingBody with the section between marker's start/end sentinels
+// replaced by newContent, or existingBody with the marked block appended if no such section exists.
+func spliceMarkedContent(existingBody, marker, newContent string) string {
+	start := startMarker(marker)
+	end := endMarker(marker)
+	block := start + "\n" + newContent + "\n" + end
+
+	startIdx := strings.Index(existingBody, start)
+	endIdx := strings.Index(existingBody, end)
+	if startIdx == -1 || endIdx == -1 || endIdx < startIdx {
+		if existingBody == "" {
+			return block
+		}
+		return existingBody + "\n" + block
+	}
+
+	return existingBody[:startIdx] + block + existingBody[endIdx+len(end):]
+}
+
+// spliceMarkedADF returns existingDoc with the top-level nodes between marker's start/end
+// sentinel paragraphs replaced by comment's content (comment.ADF verbatim, or comment.Content
+// wrapped in a single paragraph), or existingDoc with the marked block appended if no such
+// section exists.
+func spliceMarkedADF(existingDoc ADFNode, comment Comment) ADFNode {
+	newContent := comment.ADF
+	if newContent == nil {
+		doc := NewADFDoc().Paragraph(comment.Content).Build()
+		newContent = &doc
+	}
+
+	start := startMarker(comment.Marker)
+	end := endMarker(comment.Marker)
+
+	block := make([]ADFNode, 0, len(newContent.Content)+2)
+	block = append(block, adfMarkerNode(start))
+	block = append(block, newContent.Content...)
+	block = append(block, adfMarkerNode(end))
+
+	startIdx, endIdx := -1, -1
+	for i, node := range existingDoc.Content {
+		if startIdx == -1 && node.flattenText() == start {
+			startIdx = i
+		}
+		if node.flattenText() == end {
+			endIdx = i
+		}
+	}
+
+	if startIdx == -1 || endIdx == -1 || endIdx < startIdx {
+		existingDoc.Content = append(existingDoc.Content, block...)
+		return existingDoc
+	}
+
+	content := make([]ADFNode, 0, len(existingDoc.Content)-(endIdx-startIdx+1)+len(block))
+	content = append(content, existingDoc.Content[:startIdx]...)
+	content = append(content, block...)
+	content = append(content, existingDoc.Content[endIdx+1:]...)
+	existingDoc.Content = content
+
+	return existingDoc
+}
@@ -0,0 +1,100 @@
+package jira
+
+import "testing"
+
+func TestSpliceMarkedContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string
+		marker     string
+		newContent string
+		want       string
+	}{
+		{
+			name:       "no existing block appends it",
+			existing:   "Some unrelated text left by a user.",
+			marker:     "bitrise-build-status",
+			newContent: "Build #1 passed",
+			want:       "Some unrelated text left by a user.\n<!-- bitrise-build-status start -->\nBuild #1 passed\n<!-- bitrise-build-status end -->",
+		},
+		{
+			name:       "empty existing body",
+			existing:   "",
+			marker:     "bitrise-build-status",
+			newContent: "Build #1 passed",
+			want:       "<!-- bitrise-build-status start -->\nBuild #1 passed\n<!-- bitrise-build-status end -->",
+		},
+		{
+			name:       "existing block is replaced in place, surrounding text kept",
+			existing:   "Before.\n<!-- bitrise-build-status start -->\nBuild #1 passed\n<!-- bitrise-build-status end -->\nAfter.",
+			marker:     "bitrise-build-status",
+			newContent: "Build #2 failed",
+			want:       "Before.\n<!-- bitrise-build-status start -->\nBuild #2 failed\n<!-- bitrise-build-status end -->\nAfter.",
+		},
+		{
+			name:       "reordered markers are treated as no existing block",
+			existing:   "<!-- bitrise-build-status end -->\n<!-- bitrise-build-status start -->",
+			marker:     "bitrise-build-status",
+			newContent: "Build #1 passed",
+			want:       "<!-- bitrise-build-status end -->\n<!-- bitrise-build-status start -->\n<!-- bitrise-build-status start -->\nBuild #1 passed\n<!-- bitrise-build-status end -->",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := spliceMarkedContent(test.existing, test.marker, test.newContent); got != test.want {
+				t.Errorf("spliceMarkedContent(%q, %q, %q) = %q, want %q", test.existing, test.marker, test.newContent, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSpliceMarkedADF(t *testing.T) {
+	marker := "bitrise-build-status"
+
+	t.Run("no existing block appends it", func(t *testing.T) {
+		existing := NewADFDoc().Paragraph("unrelated").Build()
+		comment := Comment{Marker: marker, Content: "Build #1 passed"}
+
+		result := spliceMarkedADF(existing, comment)
+
+		if len(result.Content) != 4 {
+			t.Fatalf("content length = %d, want 4 (unrelated + start + body + end)", len(result.Content))
+		}
+		if got, want := result.Content[1].flattenText(), startMarker(marker); got != want {
+			t.Errorf("start marker node text = %q, want %q", got, want)
+		}
+		if got, want := result.Content[3].flattenText(), endMarker(marker); got != want {
+			t.Errorf("end marker node text = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("existing block is replaced in place, surrounding nodes kept", func(t *testing.T) {
+		existing := ADFNode{
+			Type: "doc",
+			Content: []ADFNode{
+				{Type: "paragraph", Content: []ADFNode{{Type: "text", Text: "Before."}}},
+				adfMarkerNode(startMarker(marker)),
+				{Type: "paragraph", Content: []ADFNode{{Type: "text", Text: "Build #1 passed"}}},
+				adfMarkerNode(endMarker(marker)),
+				{Type: "paragraph", Content: []ADFNode{{Type: "text", Text: "After."}}},
+			},
+		}
+		comment := Comment{Marker: marker, Content: "Build #2 failed"}
+
+		result := spliceMarkedADF(existing, comment)
+
+		if len(result.Content) != 5 {
+			t.Fatalf("content length = %d, want 5", len(result.Content))
+		}
+		if got, want := result.Content[0].flattenText(), "Before."; got != want {
+			t.Errorf("leading node = %q, want %q", got, want)
+		}
+		if got, want := result.Content[2].flattenText(), "Build #2 failed"; got != want {
+			t.Errorf("replaced body = %q, want %q", got, want)
+		}
+		if got, want := result.Content[4].flattenText(), "After."; got != want {
+			t.Errorf("trailing node = %q, want %q", got, want)
+		}
+	})
+}
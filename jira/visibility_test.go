@@ -0,0 +1,56 @@
+package jira
+
+import "testing"
+
+func TestResolveVisibility(t *testing.T) {
+	defaultVisibility := &Visibility{Type: "role", Value: "Developers"}
+	client := &Client{defaultVisibility: defaultVisibility}
+
+	t.Run("comment visibility overrides the client default", func(t *testing.T) {
+		commentVisibility := &Visibility{Type: "group", Value: "internal-only"}
+		comment := Comment{Visibility: commentVisibility}
+
+		if got := client.resolveVisibility(comment); got != commentVisibility {
+			t.Errorf("resolveVisibility() = %+v, want %+v", got, commentVisibility)
+		}
+	})
+
+	t.Run("falls back to the client default", func(t *testing.T) {
+		if got := client.resolveVisibility(Comment{}); got != defaultVisibility {
+			t.Errorf("resolveVisibility() = %+v, want %+v", got, defaultVisibility)
+		}
+	})
+
+	t.Run("nil when neither is set", func(t *testing.T) {
+		noDefault := &Client{}
+		if got := noDefault.resolveVisibility(Comment{}); got != nil {
+			t.Errorf("resolveVisibility() = %+v, want nil", got)
+		}
+	})
+}
+
+func TestApplyVisibility(t *testing.T) {
+	client := &Client{defaultVisibility: &Visibility{Type: "role", Value: "Developers"}}
+	fields := map[string]interface{}{"body": "some text"}
+
+	client.applyVisibility(fields, Comment{})
+
+	visibility, ok := fields["visibility"].(map[string]string)
+	if !ok {
+		t.Fatalf("fields[\"visibility\"] = %#v, want a map[string]string", fields["visibility"])
+	}
+	if visibility["type"] != "role" || visibility["value"] != "Developers" {
+		t.Errorf("visibility = %+v, want role/Developers", visibility)
+	}
+}
+
+func TestApplyVisibilityNoop(t *testing.T) {
+	client := &Client{}
+	fields := map[string]interface{}{"body": "some text"}
+
+	client.applyVisibility(fields, Comment{})
+
+	if _, ok := fields["visibility"]; ok {
+		t.Errorf("fields[\"visibility\"] = %#v, want no visibility key when none is configured", fields["visibility"])
+	}
+}